@@ -0,0 +1,669 @@
+// Package filter implements the --wordlist-filter predicate language: a
+// small expression DSL for deciding whether a wordlist line should be
+// excluded, compiled once into an AST and evaluated per line.
+//
+// Grammar:
+//
+//	expr       = orExpr
+//	orExpr     = andExpr ("or" andExpr)*
+//	andExpr    = unary ("and" unary)*
+//	unary      = "not" unary | primary
+//	primary    = "(" expr ")" | call | lenExpr | entropyExpr | ident
+//	call       = ident "(" arg ("," arg)* ")"
+//	arg        = STRING | REGEX
+//	lenExpr    = "len" ">" NUMBER | "len" "between" NUMBER ".." NUMBER
+//	entropyExpr = "entropy" ">" NUMBER
+//	ident      = "allupper" | "alllower" | "startupper" | "startlower" | "startdigit"
+package filter
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Position locates a token within a filter expression, for error messages.
+type Position struct {
+	Column int
+}
+
+// node is a compiled predicate: it reports whether line matches and
+// renders its own surface syntax back for --filter-explain.
+type node interface {
+	match(line []byte) bool
+	String() string
+}
+
+// Predicate is a compiled, AND-combination of one or more --wordlist-filter
+// expressions, ready to be applied to wordlist lines.
+type Predicate struct {
+	root node
+}
+
+// Compile parses each expression in exprs (skipping blank ones) and
+// AND-combines them into a single Predicate, mirroring how repeated
+// --wordlist-filter flags are combined on the command line.
+func Compile(exprs ...string) (*Predicate, error) {
+	var nodes []node
+	for _, expr := range exprs {
+		if strings.TrimSpace(expr) == "" {
+			continue
+		}
+		n, err := parse(expr)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	if len(nodes) == 0 {
+		return &Predicate{root: literal(false)}, nil
+	}
+	if len(nodes) == 1 {
+		return &Predicate{root: nodes[0]}, nil
+	}
+	return &Predicate{root: &andNode{children: nodes}}, nil
+}
+
+// Match reports whether line should be excluded.
+func (p *Predicate) Match(line []byte) bool {
+	return p.root.match(line)
+}
+
+// Explain reports whether line should be excluded and, if so, which
+// sub-expression was responsible, for --filter-explain.
+func (p *Predicate) Explain(line []byte) (bool, string) {
+	if !p.root.match(line) {
+		return false, ""
+	}
+	return true, firstTrueLeaf(p.root, line)
+}
+
+func firstTrueLeaf(n node, line []byte) string {
+	switch v := n.(type) {
+	case *andNode:
+		for _, c := range v.children {
+			if c.match(line) {
+				return firstTrueLeaf(c, line)
+			}
+		}
+	case *orNode:
+		for _, c := range v.children {
+			if c.match(line) {
+				return firstTrueLeaf(c, line)
+			}
+		}
+	}
+	return n.String()
+}
+
+type literal bool
+
+func (l literal) match([]byte) bool { return bool(l) }
+func (l literal) String() string    { return fmt.Sprintf("%t", bool(l)) }
+
+type andNode struct{ children []node }
+
+func (n *andNode) match(line []byte) bool {
+	for _, c := range n.children {
+		if !c.match(line) {
+			return false
+		}
+	}
+	return true
+}
+func (n *andNode) String() string { return joinNodes(n.children, " and ") }
+
+type orNode struct{ children []node }
+
+func (n *orNode) match(line []byte) bool {
+	for _, c := range n.children {
+		if c.match(line) {
+			return true
+		}
+	}
+	return false
+}
+func (n *orNode) String() string { return joinNodes(n.children, " or ") }
+
+type notNode struct{ child node }
+
+func (n *notNode) match(line []byte) bool { return !n.child.match(line) }
+func (n *notNode) String() string         { return "not " + n.child.String() }
+
+func joinNodes(nodes []node, sep string) string {
+	parts := make([]string, len(nodes))
+	for i, n := range nodes {
+		parts[i] = n.String()
+	}
+	return "(" + strings.Join(parts, sep) + ")"
+}
+
+// --- primitives ---
+
+type startsWithNode string
+
+func (n startsWithNode) match(line []byte) bool { return strings.HasPrefix(string(line), string(n)) }
+func (n startsWithNode) String() string         { return fmt.Sprintf("startswith(%q)", string(n)) }
+
+type endsWithNode string
+
+func (n endsWithNode) match(line []byte) bool { return strings.HasSuffix(string(line), string(n)) }
+func (n endsWithNode) String() string         { return fmt.Sprintf("endswith(%q)", string(n)) }
+
+type containsNode string
+
+func (n containsNode) match(line []byte) bool { return strings.Contains(string(line), string(n)) }
+func (n containsNode) String() string         { return fmt.Sprintf("contains(%q)", string(n)) }
+
+type matchesNode struct {
+	src string
+	re  *regexp.Regexp
+}
+
+func (n *matchesNode) match(line []byte) bool { return n.re.Match(line) }
+func (n *matchesNode) String() string         { return fmt.Sprintf("matches(/%s/)", n.src) }
+
+type hasExtNode []string
+
+func (n hasExtNode) match(line []byte) bool {
+	text := string(line)
+	for _, ext := range n {
+		if strings.HasSuffix(text, ext) {
+			return true
+		}
+	}
+	return false
+}
+func (n hasExtNode) String() string {
+	quoted := make([]string, len(n))
+	for i, e := range n {
+		quoted[i] = fmt.Sprintf("%q", e)
+	}
+	return fmt.Sprintf("hasext(%s)", strings.Join(quoted, ","))
+}
+
+type lenGtNode int
+
+func (n lenGtNode) match(line []byte) bool { return utf8.RuneCount(line) > int(n) }
+func (n lenGtNode) String() string         { return fmt.Sprintf("len > %d", int(n)) }
+
+type lenBetweenNode struct{ lo, hi int }
+
+func (n lenBetweenNode) match(line []byte) bool {
+	l := utf8.RuneCount(line)
+	return l >= n.lo && l <= n.hi
+}
+func (n lenBetweenNode) String() string { return fmt.Sprintf("len between %d..%d", n.lo, n.hi) }
+
+type entropyGtNode float64
+
+func (n entropyGtNode) match(line []byte) bool { return shannonEntropy(line) > float64(n) }
+func (n entropyGtNode) String() string         { return fmt.Sprintf("entropy > %g", float64(n)) }
+
+func shannonEntropy(line []byte) float64 {
+	if len(line) == 0 {
+		return 0
+	}
+	var counts [256]int
+	for _, b := range line {
+		counts[b]++
+	}
+	var entropy float64
+	total := float64(len(line))
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / total
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+type allUpperNode struct{}
+
+func (allUpperNode) match(line []byte) bool {
+	hasLetter := false
+	for _, r := range string(line) {
+		if unicode.IsLetter(r) {
+			hasLetter = true
+			if !unicode.IsUpper(r) {
+				return false
+			}
+		}
+	}
+	return hasLetter
+}
+func (allUpperNode) String() string { return "allupper" }
+
+type allLowerNode struct{}
+
+func (allLowerNode) match(line []byte) bool {
+	hasLetter := false
+	for _, r := range string(line) {
+		if unicode.IsLetter(r) {
+			hasLetter = true
+			if !unicode.IsLower(r) {
+				return false
+			}
+		}
+	}
+	return hasLetter
+}
+func (allLowerNode) String() string { return "alllower" }
+
+type startUpperNode struct{}
+
+func (startUpperNode) match(line []byte) bool {
+	r, _ := utf8.DecodeRune(line)
+	return unicode.IsUpper(r)
+}
+func (startUpperNode) String() string { return "startupper" }
+
+type startLowerNode struct{}
+
+func (startLowerNode) match(line []byte) bool {
+	r, _ := utf8.DecodeRune(line)
+	return unicode.IsLower(r)
+}
+func (startLowerNode) String() string { return "startlower" }
+
+type startDigitNode struct{}
+
+func (startDigitNode) match(line []byte) bool {
+	r, _ := utf8.DecodeRune(line)
+	return unicode.IsDigit(r)
+}
+func (startDigitNode) String() string { return "startdigit" }
+
+// --- parser ---
+
+// ParseError reports a malformed --wordlist-filter expression, with the
+// column the parser gave up at.
+type ParseError struct {
+	Expr string
+	Pos  Position
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("wordlist-filter: %s (at column %d): %s", e.Expr, e.Pos.Column, e.Msg)
+}
+
+func parse(expr string) (node, error) {
+	p := &parser{lexer: newLexer(expr), expr: expr}
+	p.advance()
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected trailing input %q", p.tok.text)
+	}
+	return n, nil
+}
+
+type parser struct {
+	lexer *lexer
+	expr  string
+	tok   token
+}
+
+func (p *parser) advance() { p.tok = p.lexer.next() }
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &ParseError{Expr: p.expr, Pos: Position{Column: p.tok.col}, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	children := []node{left}
+	for p.tok.kind == tokIdent && p.tok.text == "or" {
+		p.advance()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return &orNode{children: children}, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	children := []node{left}
+	for p.tok.kind == tokIdent && p.tok.text == "and" {
+		p.advance()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return &andNode{children: children}, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.tok.kind == tokIdent && p.tok.text == "not" {
+		p.advance()
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{child: child}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	switch {
+	case p.tok.kind == tokLParen:
+		p.advance()
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.errorf("expected ')'")
+		}
+		p.advance()
+		return n, nil
+	case p.tok.kind == tokIdent:
+		return p.parseIdentExpr()
+	default:
+		return nil, p.errorf("unexpected token %q", p.tok.text)
+	}
+}
+
+func (p *parser) parseIdentExpr() (node, error) {
+	name := p.tok.text
+	p.advance()
+	switch name {
+	case "startswith", "endswith", "contains", "hasext":
+		args, err := p.parseArgs()
+		if err != nil {
+			return nil, err
+		}
+		return buildArgNode(name, args)
+	case "matches":
+		args, err := p.parseArgs()
+		if err != nil {
+			return nil, err
+		}
+		if len(args) != 1 {
+			return nil, p.errorf("matches(...) expects exactly one regex argument")
+		}
+		re, err := regexp.Compile(args[0])
+		if err != nil {
+			return nil, p.errorf("invalid regex %q: %v", args[0], err)
+		}
+		return &matchesNode{src: args[0], re: re}, nil
+	case "len":
+		return p.parseLenExpr()
+	case "entropy":
+		return p.parseEntropyExpr()
+	case "allupper":
+		return allUpperNode{}, nil
+	case "alllower":
+		return allLowerNode{}, nil
+	case "startupper":
+		return startUpperNode{}, nil
+	case "startlower":
+		return startLowerNode{}, nil
+	case "startdigit":
+		return startDigitNode{}, nil
+	default:
+		return nil, p.errorf("unknown predicate %q", name)
+	}
+}
+
+func (p *parser) parseArgs() ([]string, error) {
+	if p.tok.kind != tokLParen {
+		return nil, p.errorf("expected '(' after predicate name")
+	}
+	p.advance()
+	var args []string
+	for p.tok.kind != tokRParen {
+		if p.tok.kind != tokString && p.tok.kind != tokRegex {
+			return nil, p.errorf("expected a quoted string or /regex/ argument")
+		}
+		args = append(args, p.tok.text)
+		p.advance()
+		if p.tok.kind == tokComma {
+			p.advance()
+			continue
+		}
+		break
+	}
+	if p.tok.kind != tokRParen {
+		return nil, p.errorf("expected ')'")
+	}
+	p.advance()
+	return args, nil
+}
+
+func buildArgNode(name string, args []string) (node, error) {
+	switch name {
+	case "startswith":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("startswith(...) expects exactly one argument")
+		}
+		return startsWithNode(args[0]), nil
+	case "endswith":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("endswith(...) expects exactly one argument")
+		}
+		return endsWithNode(args[0]), nil
+	case "contains":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("contains(...) expects exactly one argument")
+		}
+		return containsNode(args[0]), nil
+	case "hasext":
+		if len(args) == 0 {
+			return nil, fmt.Errorf("hasext(...) expects at least one argument")
+		}
+		return hasExtNode(args), nil
+	default:
+		return nil, fmt.Errorf("unknown predicate %q", name)
+	}
+}
+
+func (p *parser) parseLenExpr() (node, error) {
+	if p.tok.kind == tokGT {
+		p.advance()
+		n, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		return lenGtNode(n), nil
+	}
+	if p.tok.kind == tokIdent && p.tok.text == "between" {
+		p.advance()
+		lo, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokDotDot {
+			return nil, p.errorf("expected '..' in 'len between N..M'")
+		}
+		p.advance()
+		hi, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		return lenBetweenNode{lo: lo, hi: hi}, nil
+	}
+	return nil, p.errorf("expected '>' or 'between' after 'len'")
+}
+
+func (p *parser) parseEntropyExpr() (node, error) {
+	if p.tok.kind != tokGT {
+		return nil, p.errorf("expected '>' after 'entropy'")
+	}
+	p.advance()
+	f, err := p.parseFloat()
+	if err != nil {
+		return nil, err
+	}
+	return entropyGtNode(f), nil
+}
+
+func (p *parser) parseNumber() (int, error) {
+	if p.tok.kind != tokNumber {
+		return 0, p.errorf("expected a number")
+	}
+	n, err := strconv.Atoi(p.tok.text)
+	if err != nil {
+		return 0, p.errorf("invalid number %q", p.tok.text)
+	}
+	p.advance()
+	return n, nil
+}
+
+func (p *parser) parseFloat() (float64, error) {
+	if p.tok.kind != tokNumber {
+		return 0, p.errorf("expected a number")
+	}
+	f, err := strconv.ParseFloat(p.tok.text, 64)
+	if err != nil {
+		return 0, p.errorf("invalid number %q", p.tok.text)
+	}
+	p.advance()
+	return f, nil
+}
+
+// --- lexer ---
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokRegex
+	tokNumber
+	tokLParen
+	tokRParen
+	tokComma
+	tokGT
+	tokDotDot
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	col  int
+}
+
+type lexer struct {
+	src []rune
+	pos int
+}
+
+func newLexer(expr string) *lexer {
+	return &lexer{src: []rune(expr)}
+}
+
+func (l *lexer) next() token {
+	for l.pos < len(l.src) && (l.src[l.pos] == ' ' || l.src[l.pos] == '\t') {
+		l.pos++
+	}
+	col := l.pos + 1
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, col: col}
+	}
+	r := l.src[l.pos]
+	switch r {
+	case '(':
+		l.pos++
+		return token{kind: tokLParen, col: col}
+	case ')':
+		l.pos++
+		return token{kind: tokRParen, col: col}
+	case ',':
+		l.pos++
+		return token{kind: tokComma, col: col}
+	case '>':
+		l.pos++
+		return token{kind: tokGT, col: col}
+	case '.':
+		if l.pos+1 < len(l.src) && l.src[l.pos+1] == '.' {
+			l.pos += 2
+			return token{kind: tokDotDot, col: col}
+		}
+	case '"':
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.src) && l.src[l.pos] != '"' {
+			l.pos++
+		}
+		text := string(l.src[start:l.pos])
+		if l.pos < len(l.src) {
+			l.pos++
+		}
+		return token{kind: tokString, text: text, col: col}
+	case '/':
+		// A /regex/ literal, as matches(/^admin/) expects. "\/" escapes a
+		// literal slash inside the pattern; any other backslash sequence
+		// (e.g. \d, \s) is passed through untouched for regexp.Compile.
+		l.pos++
+		var sb strings.Builder
+		for l.pos < len(l.src) && l.src[l.pos] != '/' {
+			if l.src[l.pos] == '\\' && l.pos+1 < len(l.src) && l.src[l.pos+1] == '/' {
+				sb.WriteRune('/')
+				l.pos += 2
+				continue
+			}
+			sb.WriteRune(l.src[l.pos])
+			l.pos++
+		}
+		text := sb.String()
+		if l.pos < len(l.src) {
+			l.pos++
+		}
+		return token{kind: tokRegex, text: text, col: col}
+	}
+	if unicode.IsDigit(r) || r == '-' {
+		start := l.pos
+		l.pos++
+		for l.pos < len(l.src) && unicode.IsDigit(l.src[l.pos]) {
+			l.pos++
+		}
+		// A single '.' followed by a digit is a decimal point (for entropy's
+		// float threshold); a '.' followed by another '.' is the "N..M" range
+		// separator in "len between N..M" and must not be swallowed here.
+		if l.pos+1 < len(l.src) && l.src[l.pos] == '.' && l.src[l.pos+1] != '.' {
+			l.pos++
+			for l.pos < len(l.src) && unicode.IsDigit(l.src[l.pos]) {
+				l.pos++
+			}
+		}
+		return token{kind: tokNumber, text: string(l.src[start:l.pos]), col: col}
+	}
+	if unicode.IsLetter(r) || r == '_' {
+		start := l.pos
+		for l.pos < len(l.src) && (unicode.IsLetter(l.src[l.pos]) || unicode.IsDigit(l.src[l.pos]) || l.src[l.pos] == '_') {
+			l.pos++
+		}
+		return token{kind: tokIdent, text: string(l.src[start:l.pos]), col: col}
+	}
+	l.pos++
+	return token{kind: tokIdent, text: string(r), col: col}
+}
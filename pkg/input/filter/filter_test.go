@@ -0,0 +1,129 @@
+package filter
+
+import "testing"
+
+func TestMatchRegexLiteral(t *testing.T) {
+	pred, err := Compile(`matches(/^admin/)`)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if !pred.Match([]byte("admin-panel")) {
+		t.Errorf("expected %q to match", "admin-panel")
+	}
+	if pred.Match([]byte("user-admin")) {
+		t.Errorf("expected %q not to match", "user-admin")
+	}
+}
+
+func TestMatchRegexLiteralEscapedSlash(t *testing.T) {
+	pred, err := Compile(`matches(/^a\/b/)`)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if !pred.Match([]byte("a/b/c")) {
+		t.Errorf("expected %q to match", "a/b/c")
+	}
+}
+
+func TestMatchRegexStringRoundTrip(t *testing.T) {
+	pred, err := Compile(`matches("^admin")`)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if !pred.Match([]byte("admin-panel")) {
+		t.Errorf("expected quoted-string form to match the same as /regex/ form")
+	}
+}
+
+func TestLenBetween(t *testing.T) {
+	pred, err := Compile(`len between 3..5`)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	cases := map[string]bool{
+		"ab":     false,
+		"abc":    true,
+		"abcde":  true,
+		"abcdef": false,
+	}
+	for in, want := range cases {
+		if got := pred.Match([]byte(in)); got != want {
+			t.Errorf("Match(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestEntropyGt(t *testing.T) {
+	pred, err := Compile(`entropy > 3`)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if pred.Match([]byte("aaaaaaaa")) {
+		t.Errorf("expected low-entropy line not to match")
+	}
+	if !pred.Match([]byte("AbC1dE2fG3hI4jK5")) {
+		t.Errorf("expected high-entropy line to match")
+	}
+}
+
+func TestAllUpperAllLowerRequireLetter(t *testing.T) {
+	upper, err := Compile(`allupper`)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if upper.Match([]byte("12345")) {
+		t.Errorf("allupper should not match a digits-only line")
+	}
+	if !upper.Match([]byte("ADMIN1")) {
+		t.Errorf("allupper should match an uppercase-with-digits line")
+	}
+
+	lower, err := Compile(`alllower`)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if lower.Match([]byte("12345")) {
+		t.Errorf("alllower should not match a digits-only line")
+	}
+	if !lower.Match([]byte("admin1")) {
+		t.Errorf("alllower should match a lowercase-with-digits line")
+	}
+}
+
+func TestAndOrNot(t *testing.T) {
+	pred, err := Compile(`startswith("admin") and not endswith(".bak")`)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if !pred.Match([]byte("admin-panel")) {
+		t.Errorf("expected match")
+	}
+	if pred.Match([]byte("admin-panel.bak")) {
+		t.Errorf("expected no match")
+	}
+	if pred.Match([]byte("user-panel")) {
+		t.Errorf("expected no match")
+	}
+}
+
+func TestCompileMultipleExpressionsAreAndCombined(t *testing.T) {
+	pred, err := Compile(`startswith("a")`, `len > 2`)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if pred.Match([]byte("ab")) {
+		t.Errorf("expected short match to be rejected by the second expression")
+	}
+	if !pred.Match([]byte("abc")) {
+		t.Errorf("expected line satisfying both expressions to match")
+	}
+}
+
+func TestCompileInvalidExpression(t *testing.T) {
+	if _, err := Compile(`matches(/[/)`); err == nil {
+		t.Fatal("expected an error for an invalid regex")
+	}
+	if _, err := Compile(`bogus(1)`); err == nil {
+		t.Fatal("expected an error for an unknown predicate")
+	}
+}
@@ -0,0 +1,535 @@
+package input
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// Position locates a token or parse error in a structured wordlist file.
+// It mirrors the shape used by golang.org/x/mod's modfile reader.
+type Position struct {
+	Line   int
+	Column int
+	Byte   int
+}
+
+// String formats a Position as "line:column", suitable for prefixing a
+// "path:line:column: message" diagnostic.
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// EntryMeta carries the per-line annotations a structured wordlist can
+// attach to a payload, e.g. `admin {ext: .php,.aspx}` or `login {method: POST}`.
+// The runner consumes this when constructing the request for an entry.
+type EntryMeta struct {
+	Extensions []string
+	Method     string
+	Headers    map[string]string
+}
+
+type structuredEntry struct {
+	word string
+	pos  Position
+	meta EntryMeta
+}
+
+// tokenKind classifies a single token produced by the structured wordlist
+// scanner.
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokString
+	tokLBrace
+	tokRBrace
+	tokLParen
+	tokRParen
+	tokComma
+	tokColon
+	tokComment
+	tokNewline
+	tokBackslashNL
+	tokEOF
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  Position
+}
+
+// structuredScanner walks a structured wordlist rune by rune, tracking
+// Position so that parse errors can point at the exact rune.
+type structuredScanner struct {
+	src    []byte
+	offset int
+	line   int
+	column int
+}
+
+func newStructuredScanner(src []byte) *structuredScanner {
+	return &structuredScanner{src: src, line: 1, column: 1}
+}
+
+func (s *structuredScanner) pos() Position {
+	return Position{Line: s.line, Column: s.column, Byte: s.offset}
+}
+
+func (s *structuredScanner) peekRune() (rune, int) {
+	if s.offset >= len(s.src) {
+		return 0, 0
+	}
+	r, size := utf8.DecodeRune(s.src[s.offset:])
+	return r, size
+}
+
+func (s *structuredScanner) advance() rune {
+	r, size := s.peekRune()
+	s.offset += size
+	if r == '\n' {
+		s.line++
+		s.column = 1
+	} else {
+		s.column++
+	}
+	return r
+}
+
+// isWordRune reports whether r can appear inside a bareword token.
+func isWordRune(r rune) bool {
+	switch r {
+	case 0, ' ', '\t', '\r', '\n', '{', '}', '(', ')', ',', ':', '#', '"':
+		return false
+	default:
+		return true
+	}
+}
+
+// Scan returns the next token in the stream.
+func (s *structuredScanner) Scan() token {
+	for {
+		r, size := s.peekRune()
+		if size == 0 {
+			return token{kind: tokEOF, pos: s.pos()}
+		}
+		if r == ' ' || r == '\t' || r == '\r' {
+			s.advance()
+			continue
+		}
+		if r == '\\' {
+			// Backslash immediately followed by a newline is a line
+			// continuation: swallow both and keep scanning the next
+			// physical line as part of the same logical line.
+			save := *s
+			start := s.pos()
+			s.advance()
+			nr, nsize := s.peekRune()
+			if nsize > 0 && nr == '\n' {
+				s.advance()
+				return token{kind: tokBackslashNL, pos: start}
+			}
+			*s = save
+		}
+		break
+	}
+
+	start := s.pos()
+	r, _ := s.peekRune()
+	switch r {
+	case '\n':
+		s.advance()
+		return token{kind: tokNewline, pos: start}
+	case '#':
+		for {
+			r, size := s.peekRune()
+			if size == 0 || r == '\n' {
+				break
+			}
+			s.advance()
+		}
+		return token{kind: tokComment, pos: start}
+	case '{':
+		s.advance()
+		return token{kind: tokLBrace, pos: start}
+	case '}':
+		s.advance()
+		return token{kind: tokRBrace, pos: start}
+	case '(':
+		s.advance()
+		return token{kind: tokLParen, pos: start}
+	case ')':
+		s.advance()
+		return token{kind: tokRParen, pos: start}
+	case ',':
+		s.advance()
+		return token{kind: tokComma, pos: start}
+	case ':':
+		s.advance()
+		return token{kind: tokColon, pos: start}
+	case '"':
+		s.advance()
+		var sb strings.Builder
+		for {
+			r, size := s.peekRune()
+			if size == 0 || r == '\n' {
+				break
+			}
+			s.advance()
+			if r == '"' {
+				break
+			}
+			sb.WriteRune(r)
+		}
+		return token{kind: tokString, text: sb.String(), pos: start}
+	default:
+		var sb strings.Builder
+		for isWordRune(r) {
+			if r == '\\' {
+				// Don't swallow a line-continuation backslash into the word
+				// just because it directly abuts one with no separating
+				// space - end the word here and let the next Scan() call
+				// see the backslash at the top, where the continuation
+				// check above applies.
+				save := *s
+				s.advance()
+				nr, nsize := s.peekRune()
+				*s = save
+				if nsize > 0 && nr == '\n' {
+					break
+				}
+			}
+			sb.WriteRune(s.advance())
+			r, _ = s.peekRune()
+		}
+		return token{kind: tokWord, text: sb.String(), pos: start}
+	}
+}
+
+// ParseWarning is a non-fatal structured wordlist diagnostic: an unknown
+// directive or annotation that the parser skipped rather than aborted on.
+type ParseWarning struct {
+	Pos     Position
+	Message string
+}
+
+func (w ParseWarning) String() string {
+	return fmt.Sprintf("%s: %s", w.Pos, w.Message)
+}
+
+// structuredParser is a small recursive-descent parser over the token
+// stream produced by structuredScanner. It folds `set ext (...)` directives
+// into an active extension set applied to the plain words that follow.
+type structuredParser struct {
+	scanner    *structuredScanner
+	dir        string
+	activeExts []string
+	warnings   []ParseWarning
+}
+
+func parseStructuredFile(path string) ([]structuredEntry, []ParseWarning, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := &structuredParser{
+		scanner: newStructuredScanner(src),
+		dir:     filepath.Dir(path),
+	}
+	entries, err := p.parse()
+	return entries, p.warnings, err
+}
+
+func (p *structuredParser) warnf(pos Position, format string, args ...interface{}) {
+	p.warnings = append(p.warnings, ParseWarning{Pos: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+// parse reads the token stream line by line, dispatching each logical line
+// (backslash continuations already folded by the scanner) to a directive or
+// plain-word handler.
+func (p *structuredParser) parse() ([]structuredEntry, error) {
+	var entries []structuredEntry
+	var line []token
+
+	flush := func() error {
+		if len(line) == 0 {
+			return nil
+		}
+		newEntries, err := p.parseLine(line)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, newEntries...)
+		line = nil
+		return nil
+	}
+
+	for {
+		tok := p.scanner.Scan()
+		switch tok.kind {
+		case tokEOF:
+			if err := flush(); err != nil {
+				return entries, err
+			}
+			return entries, nil
+		case tokNewline:
+			if err := flush(); err != nil {
+				return entries, err
+			}
+		case tokBackslashNL:
+			// logical line continues; drop the marker
+		case tokComment:
+			// full or trailing comment; drop it from the logical line
+		default:
+			line = append(line, tok)
+		}
+	}
+}
+
+// parseLine interprets a single logical line: a `set ext (...)` directive,
+// an `include "file"` directive, or a bareword optionally followed by a
+// `{...}` metadata annotation. A word with declared extensions (from an
+// active `set ext (...)` block or its own annotation) expands into one
+// structuredEntry per extension, mirroring how WordlistInput.readFile
+// expands %EXT%/DirSearchCompat into one data entry per extension rather
+// than stuffing the whole list into a single entry.
+func (p *structuredParser) parseLine(line []token) ([]structuredEntry, error) {
+	if len(line) == 0 {
+		return nil, nil
+	}
+	head := line[0]
+	if head.kind == tokWord && head.text == "set" {
+		return nil, p.parseSet(line)
+	}
+	if head.kind == tokWord && head.text == "include" {
+		return p.parseInclude(line)
+	}
+	if head.kind != tokWord {
+		p.warnf(head.pos, "unexpected token, skipping line")
+		return nil, nil
+	}
+
+	entry := structuredEntry{word: head.text, pos: head.pos}
+	exts := p.activeExts
+	if len(line) > 1 && line[1].kind == tokLBrace {
+		meta, err := p.parseAnnotation(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if len(meta.Extensions) > 0 {
+			exts = meta.Extensions
+		}
+		if meta.Method != "" {
+			entry.meta.Method = meta.Method
+		}
+		if len(meta.Headers) > 0 {
+			entry.meta.Headers = meta.Headers
+		}
+	}
+	if len(exts) == 0 {
+		return []structuredEntry{entry}, nil
+	}
+
+	entries := make([]structuredEntry, 0, len(exts))
+	for _, ext := range exts {
+		e := entry
+		e.meta.Extensions = []string{ext}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// parseSet handles `set ext ( .php, .aspx )`, replacing the parser's active
+// extension set for every plain word line that follows until the next `set`
+// directive or end of file.
+func (p *structuredParser) parseSet(line []token) error {
+	if len(line) < 2 || line[1].text != "ext" {
+		p.warnf(line[0].pos, "unknown 'set' directive, expected 'set ext (...)'")
+		return nil
+	}
+	rest := line[2:]
+	if len(rest) == 0 || rest[0].kind != tokLParen {
+		p.warnf(line[0].pos, "expected '(' after 'set ext'")
+		return nil
+	}
+	var exts []string
+	for _, tok := range rest[1:] {
+		switch tok.kind {
+		case tokWord, tokString:
+			exts = append(exts, tok.text)
+		case tokComma, tokRParen:
+			// separators, nothing to record
+		default:
+			p.warnf(tok.pos, "unexpected token in 'set ext (...)' list")
+		}
+	}
+	p.activeExts = exts
+	return nil
+}
+
+// parseInclude handles `include "other.txt"`, recursively parsing the named
+// file (resolved relative to the including file) and splicing its entries
+// in at this point.
+func (p *structuredParser) parseInclude(line []token) ([]structuredEntry, error) {
+	if len(line) < 2 || line[1].kind != tokString {
+		p.warnf(line[0].pos, "expected a quoted path after 'include'")
+		return nil, nil
+	}
+	target := line[1].text
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(p.dir, target)
+	}
+	entries, warnings, err := parseStructuredFile(target)
+	p.warnings = append(p.warnings, warnings...)
+	return entries, err
+}
+
+// parseAnnotation parses a `{key: value, value, key2: value}` block
+// following a word, returning the metadata it describes.
+func (p *structuredParser) parseAnnotation(tokens []token) (EntryMeta, error) {
+	var meta EntryMeta
+	// tokens[0] is the LBRACE; walk key/value groups until RBRACE.
+	i := 1
+	for i < len(tokens) && tokens[i].kind != tokRBrace {
+		if tokens[i].kind != tokWord {
+			p.warnf(tokens[i].pos, "expected annotation key")
+			i++
+			continue
+		}
+		key := tokens[i].text
+		i++
+		if i >= len(tokens) || tokens[i].kind != tokColon {
+			p.warnf(tokens[i-1].pos, "expected ':' after annotation key %q", key)
+			continue
+		}
+		i++ // consume ':'
+
+		var values []string
+		for i < len(tokens) {
+			if tokens[i].kind == tokWord || tokens[i].kind == tokString {
+				values = append(values, tokens[i].text)
+				i++
+				if i < len(tokens) && tokens[i].kind == tokComma {
+					// Lookahead: "word :" starts the next key, not another value.
+					if i+2 < len(tokens) && tokens[i+1].kind == tokWord && tokens[i+2].kind == tokColon {
+						i++
+						break
+					}
+					i++
+					continue
+				}
+				break
+			}
+			break
+		}
+
+		switch strings.ToLower(key) {
+		case "ext":
+			meta.Extensions = values
+		case "method":
+			if len(values) > 0 {
+				meta.Method = values[0]
+			}
+		case "header":
+			if len(values) >= 2 {
+				if meta.Headers == nil {
+					meta.Headers = make(map[string]string)
+				}
+				meta.Headers[values[0]] = values[1]
+			}
+		default:
+			p.warnf(tokens[i-1].pos, "unknown annotation key %q", key)
+		}
+	}
+	return meta, nil
+}
+
+// StructuredWordlistInput is an InternalInputProvider backed by the
+// structured (.ffwl) wordlist dialect: directives, blocks, and per-line
+// metadata annotations, opted into via file extension or
+// --wordlist-format=structured.
+type StructuredWordlistInput struct {
+	active   bool
+	config   *ffuf.Config
+	keyword  string
+	path     string
+	position int
+	entries  []structuredEntry
+	warnings []ParseWarning
+}
+
+// NewStructuredWordlistInput parses value as a structured wordlist file and
+// returns a provider over its entries.
+func NewStructuredWordlistInput(keyword string, value string, conf *ffuf.Config) (*StructuredWordlistInput, error) {
+	entries, warnings, err := parseStructuredFile(value)
+	if err != nil {
+		return nil, err
+	}
+	if conf.Verbose {
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "%s:%s\n", value, w)
+		}
+	}
+	return &StructuredWordlistInput{
+		active:   true,
+		config:   conf,
+		keyword:  keyword,
+		path:     value,
+		entries:  entries,
+		warnings: warnings,
+	}, nil
+}
+
+// Warnings returns the non-fatal parse diagnostics collected while reading
+// the structured wordlist, e.g. unknown directives or annotation keys.
+func (w *StructuredWordlistInput) Warnings() []ParseWarning {
+	return w.warnings
+}
+
+func (w *StructuredWordlistInput) Position() int       { return w.position }
+func (w *StructuredWordlistInput) SetPosition(pos int) { w.position = pos }
+func (w *StructuredWordlistInput) ResetPosition()      { w.position = 0 }
+func (w *StructuredWordlistInput) Keyword() string     { return w.keyword }
+
+// Origin returns the source wordlist path and the position (line, column,
+// byte offset) the entry at the current cursor was parsed from. See
+// WordlistInput.Origin.
+func (w *StructuredWordlistInput) Origin() (string, Position) {
+	if w.position < len(w.entries) {
+		return w.path, w.entries[w.position].pos
+	}
+	return w.path, Position{}
+}
+
+func (w *StructuredWordlistInput) Next() bool         { return w.position < len(w.entries) }
+func (w *StructuredWordlistInput) IncrementPosition() { w.position += 1 }
+func (w *StructuredWordlistInput) Total() int         { return len(w.entries) }
+func (w *StructuredWordlistInput) Active() bool       { return w.active }
+func (w *StructuredWordlistInput) Enable()            { w.active = true }
+func (w *StructuredWordlistInput) Disable()           { w.active = false }
+
+// Value returns the payload at the current cursor position. An extension
+// declared via metadata is appended to the word, the same way -e extensions
+// are for plain FUZZ wordlists, so callers that don't care about the richer
+// metadata can keep treating this like any other InternalInputProvider.
+func (w *StructuredWordlistInput) Value() []byte {
+	entry := w.entries[w.position]
+	if len(entry.meta.Extensions) > 0 {
+		return []byte(entry.word + entry.meta.Extensions[0])
+	}
+	return []byte(entry.word)
+}
+
+// ValueMeta returns the bare word at the current cursor position, without
+// the extension that Value appends, together with its parsed metadata
+// (extensions, method override, headers), for
+// runners that want to act on the richer structured dialect directly.
+func (w *StructuredWordlistInput) ValueMeta() ([]byte, EntryMeta) {
+	entry := w.entries[w.position]
+	return []byte(entry.word), entry.meta
+}
@@ -0,0 +1,114 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeStructuredFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "wordlist.ffwl")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestParseLineExpandsOneEntryPerExtension(t *testing.T) {
+	path := writeStructuredFile(t, `admin {ext: .php,.aspx,.bak}`)
+	entries, warnings, err := parseStructuredFile(path)
+	if err != nil {
+		t.Fatalf("parseStructuredFile: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	wantExts := []string{".php", ".aspx", ".bak"}
+	for i, e := range entries {
+		if e.word != "admin" {
+			t.Errorf("entries[%d].word = %q, want %q", i, e.word, "admin")
+		}
+		if len(e.meta.Extensions) != 1 || e.meta.Extensions[0] != wantExts[i] {
+			t.Errorf("entries[%d].meta.Extensions = %v, want [%s]", i, e.meta.Extensions, wantExts[i])
+		}
+	}
+}
+
+func TestParseLineExpandsSetExtBlock(t *testing.T) {
+	path := writeStructuredFile(t, "set ext (.php, .html)\nadmin\nlogin\n")
+	entries, _, err := parseStructuredFile(path)
+	if err != nil {
+		t.Fatalf("parseStructuredFile: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("got %d entries, want 4", len(entries))
+	}
+	for i, word := range []string{"admin", "admin", "login", "login"} {
+		if entries[i].word != word {
+			t.Errorf("entries[%d].word = %q, want %q", i, entries[i].word, word)
+		}
+	}
+}
+
+func TestParseLineWithoutExtensionsProducesOneEntry(t *testing.T) {
+	path := writeStructuredFile(t, "admin\n")
+	entries, _, err := parseStructuredFile(path)
+	if err != nil {
+		t.Fatalf("parseStructuredFile: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	if len(entries[0].meta.Extensions) != 0 {
+		t.Errorf("entries[0].meta.Extensions = %v, want none", entries[0].meta.Extensions)
+	}
+}
+
+func TestScanBackslashContinuationAbuttingWord(t *testing.T) {
+	sc := newStructuredScanner([]byte("admin\\\n.bak\n"))
+	var kinds []tokenKind
+	var texts []string
+	for {
+		tok := sc.Scan()
+		kinds = append(kinds, tok.kind)
+		texts = append(texts, tok.text)
+		if tok.kind == tokEOF {
+			break
+		}
+	}
+	want := []tokenKind{tokWord, tokBackslashNL, tokWord, tokNewline, tokEOF}
+	if len(kinds) != len(want) {
+		t.Fatalf("got %d tokens %v, want %d %v", len(kinds), kinds, len(want), want)
+	}
+	for i, k := range want {
+		if kinds[i] != k {
+			t.Fatalf("token %d kind = %v, want %v", i, kinds[i], k)
+		}
+	}
+	if texts[0] != "admin" {
+		t.Errorf("word before continuation = %q, want %q (no trailing backslash)", texts[0], "admin")
+	}
+	if texts[2] != ".bak" {
+		t.Errorf("word after continuation = %q, want %q", texts[2], ".bak")
+	}
+}
+
+func TestParseLineAnnotationOverridesActiveExts(t *testing.T) {
+	path := writeStructuredFile(t, "set ext (.php)\nadmin {ext: .bak,.old}\n")
+	entries, _, err := parseStructuredFile(path)
+	if err != nil {
+		t.Fatalf("parseStructuredFile: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	for i, ext := range []string{".bak", ".old"} {
+		if entries[i].meta.Extensions[0] != ext {
+			t.Errorf("entries[%d].meta.Extensions[0] = %q, want %q", i, entries[i].meta.Extensions[0], ext)
+		}
+	}
+}
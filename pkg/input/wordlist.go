@@ -2,20 +2,25 @@ package input
 
 import (
 	"bufio"
+	"fmt"
+	"io"
 	"os"
 	"regexp"
-	"unicode"
 	"strings"
+	"sync"
 
 	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+	"github.com/ffuf/ffuf/v2/pkg/input/filter"
 )
 
 type WordlistInput struct {
-	active   bool
-	config   *ffuf.Config
-	data     [][]byte
-	position int
-	keyword  string
+	active    bool
+	config    *ffuf.Config
+	data      [][]byte
+	positions []Position
+	position  int
+	keyword   string
+	path      string
 }
 
 func NewWordlistInput(keyword string, value string, conf *ffuf.Config) (*WordlistInput, error) {
@@ -23,6 +28,7 @@ func NewWordlistInput(keyword string, value string, conf *ffuf.Config) (*Wordlis
 	wl.active = true
 	wl.keyword = keyword
 	wl.config = conf
+	wl.path = value
 	wl.position = 0
 	var valid bool
 	var err error
@@ -63,6 +69,18 @@ func (w *WordlistInput) Keyword() string {
 	return w.keyword
 }
 
+// Origin returns the source wordlist path and the line the entry at the
+// current cursor position was read from, so a runner can log which
+// wordlist:line produced a given payload. An optional Origin() method on
+// InternalInputProvider would let the output package surface this; that
+// plumbing lives outside this package's scope.
+func (w *WordlistInput) Origin() (string, Position) {
+	if w.position < len(w.positions) {
+		return w.path, w.positions[w.position]
+	}
+	return w.path, Position{}
+}
+
 // Next will return a boolean telling if there's words left in the list
 func (w *WordlistInput) Next() bool {
 	return w.position < len(w.data)
@@ -98,13 +116,16 @@ func (w *WordlistInput) Disable() {
 	w.active = false
 }
 
-// validFile checks that the wordlist file exists and can be read
+// validFile checks that the wordlist file exists and can be read. path may
+// carry an archive member selector ("archive.zip!wordlist.txt"), in which
+// case only the archive itself is checked here.
 func (w *WordlistInput) validFile(path string) (bool, error) {
-	_, err := os.Stat(path)
+	archivePath := archiveMemberPath(path)
+	_, err := os.Stat(archivePath)
 	if err != nil {
 		return false, err
 	}
-	f, err := os.Open(path)
+	f, err := os.Open(archivePath)
 	if err != nil {
 		return false, err
 	}
@@ -112,31 +133,44 @@ func (w *WordlistInput) validFile(path string) (bool, error) {
 	return true, nil
 }
 
-// readFile reads the file line by line to a byte slice
+// readFile reads the file line by line to a byte slice. path may name a
+// plain text file, a gzip/bzip2/zstd-compressed file, or a zip/tar(.gz)
+// archive (optionally with a "path/to/archive.zip!member.txt" selector);
+// openWordlistSource transparently decodes whichever it is.
 func (w *WordlistInput) readFile(path string) error {
-	var file *os.File
-	var err error
+	var source io.Reader
+	var closeFn func() error
 	if path == "-" {
-		file = os.Stdin
+		source = os.Stdin
+		closeFn = func() error { return nil }
 	} else {
-		file, err = os.Open(path)
+		var err error
+		source, closeFn, err = openWordlistSource(path)
 		if err != nil {
 			return err
 		}
 	}
-	defer file.Close()
+	defer closeFn()
 
 	var data [][]byte
+	var positions []Position
 	var ok bool
-	reader := bufio.NewScanner(file)
+	reader := bufio.NewScanner(source)
 	re := regexp.MustCompile(`(?i)%ext%`)
+	lineNo := 0
+	var byteOff int64
 	for reader.Scan() {
+		lineNo++
+		pos := Position{Line: lineNo, Column: 1, Byte: int(byteOff)}
+		byteOff += int64(len(reader.Bytes())) + 1
+
 		if w.config.DirSearchCompat && len(w.config.Extensions) > 0 {
 			text := []byte(reader.Text())
 			if re.Match(text) {
 				for _, ext := range w.config.Extensions {
 					contnt := re.ReplaceAll(text, []byte(ext))
 					data = append(data, []byte(contnt))
+					positions = append(positions, pos)
 				}
 			} else {
 				text := reader.Text()
@@ -144,10 +178,12 @@ func (w *WordlistInput) readFile(path string) error {
 				if w.config.IgnoreWordlistComments {
 					text, ok = stripComments(text)
 					if !ok {
+						w.logDropped(pos, "comment line")
 						continue
 					}
 				}
 				data = append(data, []byte(text))
+				positions = append(positions, pos)
 			}
 		} else {
 			text := reader.Text()
@@ -155,27 +191,43 @@ func (w *WordlistInput) readFile(path string) error {
 			if w.config.IgnoreWordlistComments {
 				text, ok = stripComments(text)
 				if !ok {
+					w.logDropped(pos, "comment line")
 					continue
 				}
 			}
 
 			// Check if line should be excluded based on filter options
 			if shouldExcludeLine(text, w.config) {
+				w.logDropped(pos, "wordlist-filter")
 				continue
 			}
 
 			data = append(data, []byte(text))
+			positions = append(positions, pos)
 			if w.keyword == "FUZZ" && len(w.config.Extensions) > 0 {
 				for _, ext := range w.config.Extensions {
 					data = append(data, []byte(text+ext))
+					positions = append(positions, pos)
 				}
 			}
 		}
 	}
 	w.data = data
+	w.positions = positions
 	return reader.Err()
 }
 
+// logDropped writes a "path:line:col: reason" diagnostic for a line excluded
+// while reading the wordlist, the same "path:line:col: message" shape used
+// for structured wordlist parse warnings. Only printed under -v, since most
+// dropped lines (comments, filtered entries) are expected, not errors.
+func (w *WordlistInput) logDropped(pos Position, reason string) {
+	if !w.config.Verbose {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s:%s: skipping line (%s)\n", w.path, pos, reason)
+}
+
 // stripComments removes all kind of comments from the word
 func stripComments(text string) (string, bool) {
 	// If the line starts with a # ignoring any space on the left,
@@ -193,88 +245,81 @@ func stripComments(text string) (string, bool) {
 	return text[:index], true
 }
 
-// shouldExcludeLine checks if a line should be excluded based on the filter options
-func shouldExcludeLine(text string, conf *ffuf.Config) bool {
-	trimmedText := strings.TrimSpace(text)
-
-	// Skip empty lines
-	if len(trimmedText) == 0 {
-		return true
-	}
-
-	// -xc-c: Exclude lines starting with #, ~, or /
+// legacyFilterExpr desugars the older -xc-* boolean flags into an
+// equivalent --wordlist-filter expression, so shouldExcludeLine has a
+// single predicate path regardless of which flags a user set.
+func legacyFilterExpr(conf *ffuf.Config) string {
+	var parts []string
 	if conf.ExcludeCommentLines {
-		if strings.HasPrefix(trimmedText, "#") || 
-		   strings.HasPrefix(trimmedText, "~") || 
-		   strings.HasPrefix(trimmedText, "/") {
-			return true
-		}
+		parts = append(parts, `(startswith("#") or startswith("~") or startswith("/"))`)
 	}
-
-	// -xc-d: Exclude lines starting with .
 	if conf.ExcludeDotLines {
-		if strings.HasPrefix(trimmedText, ".") {
-			return true
-		}
+		parts = append(parts, `startswith(".")`)
 	}
-
-	// -xc-n: Exclude lines starting with numbers
 	if conf.ExcludeNumberLines {
-		if len(trimmedText) > 0 {
-			firstChar := trimmedText[0]
-			if firstChar >= '0' && firstChar <= '9' {
-				return true
-			}
-		}
+		parts = append(parts, `startdigit`)
 	}
-
-	// -xc-upper: Exclude lines that are entirely uppercase
 	if conf.ExcludeUppercase {
-		isUpper := true
-		for _, r := range trimmedText {
-			if unicode.IsLetter(r) && !unicode.IsUpper(r) {
-				isUpper = false
-				break
-			}
-		}
-		if isUpper && len(trimmedText) > 0 {
-			return true
-		}
+		// The old -xc-upper flag excluded a line if it contained no
+		// lowercase letter, which is vacuously true for e.g. a digits-only
+		// line - unlike allupper, which requires at least one letter.
+		// matches(/[a-z]/) reproduces that original, letter-agnostic check.
+		parts = append(parts, `not matches("[a-z]")`)
 	}
-
-	// -xc-lower: Exclude lines that are entirely lowercase
 	if conf.ExcludeLowercase {
-		isLower := true
-		for _, r := range trimmedText {
-			if unicode.IsLetter(r) && !unicode.IsLower(r) {
-				isLower = false
-				break
-			}
-		}
-		if isLower && len(trimmedText) > 0 {
-			return true
-		}
+		parts = append(parts, `not matches("[A-Z]")`)
 	}
-
-	// -xc-s-upper: Exclude lines starting with uppercase letter
 	if conf.ExcludeStartUpper {
-		if len(trimmedText) > 0 {
-			firstRune := rune(trimmedText[0])
-			if unicode.IsUpper(firstRune) {
-				return true
-			}
-		}
+		parts = append(parts, `startupper`)
 	}
-
-	// -xc-s-lower: Exclude lines starting with lowercase letter
 	if conf.ExcludeStartLower {
-		if len(trimmedText) > 0 {
-			firstRune := rune(trimmedText[0])
-			if unicode.IsLower(firstRune) {
-				return true
-			}
-		}
+		parts = append(parts, `startlower`)
 	}
+	return strings.Join(parts, " or ")
+}
 
-	return false
+// filterCache holds the compiled, per-config --wordlist-filter predicate so
+// it's only parsed once per run rather than once per wordlist line.
+var filterCache sync.Map // map[*ffuf.Config]*filter.Predicate
+
+// compiledLineFilter returns the compiled filter for conf, combining the
+// legacy -xc-* flags (desugared to an expression) with any explicit
+// --wordlist-filter expressions, AND-combined as repeated flags are.
+func compiledLineFilter(conf *ffuf.Config) (*filter.Predicate, error) {
+	if cached, ok := filterCache.Load(conf); ok {
+		return cached.(*filter.Predicate), nil
+	}
+	exprs := append([]string{legacyFilterExpr(conf)}, conf.WordlistFilters...)
+	pred, err := filter.Compile(exprs...)
+	if err != nil {
+		return nil, err
+	}
+	filterCache.Store(conf, pred)
+	return pred, nil
+}
+
+// shouldExcludeLine checks if a line should be excluded, based on the
+// compiled --wordlist-filter predicate (which also covers the legacy
+// -xc-* flags). Under conf.FilterExplain, rejected lines are logged to
+// stderr along with the sub-expression that matched.
+func shouldExcludeLine(text string, conf *ffuf.Config) bool {
+	trimmedText := strings.TrimSpace(text)
+
+	// Skip empty lines
+	if len(trimmedText) == 0 {
+		return true
+	}
+
+	pred, err := compiledLineFilter(conf)
+	if err != nil {
+		// A malformed --wordlist-filter expression shouldn't silently
+		// drop the whole wordlist; surface nothing extra instead.
+		return false
+	}
+
+	excluded, reason := pred.Explain([]byte(trimmedText))
+	if excluded && conf.FilterExplain {
+		fmt.Fprintf(os.Stderr, "wordlist-filter: rejected %q (%s)\n", trimmedText, reason)
+	}
+	return excluded
 }
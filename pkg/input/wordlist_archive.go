@@ -0,0 +1,372 @@
+package input
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// archiveMember splits "path/to/archive.zip!wordlist.txt" into the archive
+// path and the member to extract from it. member is empty when no "!" is
+// present, meaning "use the whole file" or, for archives, "concatenate
+// every *.txt member".
+func archiveMember(value string) (path string, member string) {
+	idx := strings.LastIndex(value, "!")
+	if idx == -1 {
+		return value, ""
+	}
+	return value[:idx], value[idx+1:]
+}
+
+// decompressedReader wraps f with the decompressor matching value's
+// extension, or by sniffing magic bytes when the extension doesn't say.
+// It returns f unchanged (wrapped in a *bufio.Reader) for plain text.
+func decompressedReader(f *os.File, value string) (io.Reader, error) {
+	ext := strings.ToLower(filepath.Ext(value))
+	switch ext {
+	case ".gz", ".tgz":
+		return gzip.NewReader(f)
+	case ".bz2":
+		return bzip2.NewReader(f), nil
+	case ".zst":
+		dec, err := zstd.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	}
+
+	buf := bufio.NewReader(f)
+	magic, err := buf.Peek(6)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return gzip.NewReader(buf)
+	case bytes.HasPrefix(magic, []byte{0x42, 0x5a, 0x68}): // "BZh"
+		return bzip2.NewReader(buf), nil
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		dec, err := zstd.NewReader(buf)
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	default:
+		return buf, nil
+	}
+}
+
+// isArchive reports whether value names a zip or tar(.gz) bundle rather
+// than a single (possibly compressed) wordlist file.
+func isArchive(value string) bool {
+	lower := strings.ToLower(value)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return true
+	case strings.HasSuffix(lower, ".tar"), strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return true
+	}
+	return false
+}
+
+// archiveMemberPath returns just the archive path half of a
+// "path/to/archive.zip!member.txt" value, for callers that only need to
+// test the archive file itself (e.g. with isArchive).
+func archiveMemberPath(value string) string {
+	path, _ := archiveMember(value)
+	return path
+}
+
+// needsDecompression reports whether value names a compressed (but
+// non-archive) wordlist, based on its extension. .xz is not recognized here,
+// since no xz decoder is wired in; an .xz file is read as plain text like any
+// other unrecognized extension.
+func needsDecompression(value string) bool {
+	switch strings.ToLower(filepath.Ext(value)) {
+	case ".gz", ".tgz", ".bz2", ".zst":
+		return true
+	default:
+		return false
+	}
+}
+
+// spoolDecompressed fully decodes value (compressed file or archive member)
+// into a temporary plain-text file and returns its path, so that a
+// streaming provider can seek into it the same way it would a wordlist
+// already on disk.
+func spoolDecompressed(value string) (string, error) {
+	src, closeFn, err := openWordlistSource(value)
+	if err != nil {
+		return "", err
+	}
+	defer closeFn()
+
+	tmp, err := os.CreateTemp("", "ffuf-wordlist-archive-*")
+	if err != nil {
+		return "", err
+	}
+	defer tmp.Close()
+
+	if _, err := io.Copy(tmp, src); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// openWordlistSource resolves value - a plain file, a compressed file, or
+// an archive with an optional "!member" selector - into a single reader
+// containing the effective wordlist text. Supported compressed formats are
+// gzip (.gz/.tgz), bzip2 (.bz2) and zstd (.zst); .xz is NOT supported (no xz
+// decoder is wired in) despite being a common wordlist distribution format:
+// its magic bytes match none of the sniffed formats, so a "wordlist.txt.xz"
+// is read as plain text, still compressed.
+func openWordlistSource(value string) (io.Reader, func() error, error) {
+	path, member := archiveMember(value)
+
+	if isArchive(path) {
+		return openArchiveSource(path, member)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := decompressedReader(f, path)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return r, closeReaderAndFile(r, f), nil
+}
+
+// closeReaderAndFile returns a closeFn that closes r first, when it
+// implements io.Closer, before closing f. decompressedReader's .zst path
+// wraps f in a zstd.Decoder whose own Close releases its internal goroutines
+// and buffers; closing only the backing file leaked that decoder state.
+func closeReaderAndFile(r io.Reader, f *os.File) func() error {
+	return func() error {
+		var err error
+		if rc, ok := r.(io.Closer); ok {
+			err = rc.Close()
+		}
+		if fErr := f.Close(); err == nil {
+			err = fErr
+		}
+		return err
+	}
+}
+
+// openArchiveSource extracts member from a zip or tar(.gz) archive at path.
+// When member is empty, every *.txt entry is concatenated in name order
+// with duplicate lines dropped.
+func openArchiveSource(path string, member string) (io.Reader, func() error, error) {
+	lower := strings.ToLower(path)
+	if strings.HasSuffix(lower, ".zip") {
+		return openZipSource(path, member)
+	}
+	return openTarSource(path, member)
+}
+
+func openZipSource(path string, member string) (io.Reader, func() error, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if member != "" {
+		for _, f := range zr.File {
+			if f.Name == member {
+				rc, err := f.Open()
+				if err != nil {
+					zr.Close()
+					return nil, nil, err
+				}
+				return rc, func() error { rc.Close(); return zr.Close() }, nil
+			}
+		}
+		zr.Close()
+		return nil, nil, fmt.Errorf("%s: member %q not found", path, member)
+	}
+
+	var names []string
+	for _, f := range zr.File {
+		if strings.HasSuffix(strings.ToLower(f.Name), ".txt") {
+			names = append(names, f.Name)
+		}
+	}
+	sort.Strings(names)
+
+	tmp, err := concatTxtMembers(names, func(name string) (io.ReadCloser, error) {
+		for _, f := range zr.File {
+			if f.Name == name {
+				return f.Open()
+			}
+		}
+		return nil, fmt.Errorf("member %q vanished", name)
+	})
+	zr.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	return tmp, closeAndRemove(tmp), nil
+}
+
+// newTarReader opens path for a fresh sequential pass, wrapping it in a
+// gzip decompressor first when the name says it's a .tar.gz/.tgz.
+func newTarReader(path string) (*tar.Reader, *os.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var r io.Reader = f
+	if strings.HasSuffix(strings.ToLower(path), ".gz") || strings.HasSuffix(strings.ToLower(path), ".tgz") {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+		r = gr
+	}
+	return tar.NewReader(r), f, nil
+}
+
+// tarMemberReader streams a single tar member's content off the underlying
+// tar.Reader, closing the backing file once the caller is done with it.
+type tarMemberReader struct {
+	tr *tar.Reader
+	f  *os.File
+}
+
+func (r *tarMemberReader) Read(p []byte) (int, error) { return r.tr.Read(p) }
+func (r *tarMemberReader) Close() error               { return r.f.Close() }
+
+// seekTarMember advances a fresh tar.Reader over path to the header named
+// member, returning a reader over just that member's content.
+func seekTarMember(path string, member string) (io.ReadCloser, error) {
+	tr, f, err := newTarReader(path)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			f.Close()
+			return nil, fmt.Errorf("%s: member %q not found", path, member)
+		}
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if hdr.Name == member {
+			return &tarMemberReader{tr: tr, f: f}, nil
+		}
+	}
+}
+
+func openTarSource(path string, member string) (io.Reader, func() error, error) {
+	if member != "" {
+		rc, err := seekTarMember(path, member)
+		if err != nil {
+			return nil, nil, err
+		}
+		return rc, rc.Close, nil
+	}
+
+	tr, f, err := newTarReader(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+		if strings.HasSuffix(strings.ToLower(hdr.Name), ".txt") {
+			names = append(names, hdr.Name)
+		}
+	}
+	f.Close()
+	sort.Strings(names)
+
+	tmp, err := concatTxtMembers(names, func(name string) (io.ReadCloser, error) {
+		return seekTarMember(path, name)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return tmp, closeAndRemove(tmp), nil
+}
+
+// concatTxtMembers streams each named member via open, in order, line by
+// line into a fresh temporary file, dropping duplicate lines. Member content
+// is never read whole into memory, but the set used to drop duplicates holds
+// every distinct line written so far.
+func concatTxtMembers(names []string, open func(name string) (io.ReadCloser, error)) (*os.File, error) {
+	tmp, err := os.CreateTemp("", "ffuf-wordlist-concat-*")
+	if err != nil {
+		return nil, err
+	}
+	fail := func(err error) (*os.File, error) {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range names {
+		rc, err := open(name)
+		if err != nil {
+			return fail(err)
+		}
+		scanner := bufio.NewScanner(rc)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if seen[line] {
+				continue
+			}
+			seen[line] = true
+			if _, err := fmt.Fprintln(tmp, line); err != nil {
+				rc.Close()
+				return fail(err)
+			}
+		}
+		scanErr := scanner.Err()
+		rc.Close()
+		if scanErr != nil {
+			return fail(scanErr)
+		}
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fail(err)
+	}
+	return tmp, nil
+}
+
+// closeAndRemove returns a closeFn that closes tmp and removes its backing
+// file, for the temporary files concatTxtMembers produces.
+func closeAndRemove(tmp *os.File) func() error {
+	return func() error {
+		err := tmp.Close()
+		if rmErr := os.Remove(tmp.Name()); err == nil {
+			err = rmErr
+		}
+		return err
+	}
+}
@@ -0,0 +1,199 @@
+package input
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeZipArchive(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "wordlist.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, contents := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip.Create(%q): %v", name, err)
+		}
+		if _, err := w.Write([]byte(contents)); err != nil {
+			t.Fatalf("write %q: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip.Close: %v", err)
+	}
+	return path
+}
+
+func writeTarArchive(t *testing.T, name string, files map[string]string, gzipped bool) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	defer f.Close()
+
+	var w io.Writer = f
+	var gw *gzip.Writer
+	if gzipped {
+		gw = gzip.NewWriter(f)
+		w = gw
+	}
+	tw := tar.NewWriter(w)
+	for member, contents := range files {
+		hdr := &tar.Header{Name: member, Size: int64(len(contents)), Mode: 0o644}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("tar WriteHeader(%q): %v", member, err)
+		}
+		if _, err := tw.Write([]byte(contents)); err != nil {
+			t.Fatalf("tar write %q: %v", member, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar.Close: %v", err)
+	}
+	if gw != nil {
+		if err := gw.Close(); err != nil {
+			t.Fatalf("gzip.Close: %v", err)
+		}
+	}
+	return path
+}
+
+func readAllFromSource(t *testing.T, r io.Reader, closeFn func() error) string {
+	t.Helper()
+	defer closeFn()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	return string(data)
+}
+
+func TestOpenZipSourceSelectsMember(t *testing.T) {
+	path := writeZipArchive(t, map[string]string{
+		"a.txt": "foo\nbar\n",
+		"b.txt": "baz\n",
+	})
+	r, closeFn, err := openZipSource(path, "b.txt")
+	if err != nil {
+		t.Fatalf("openZipSource: %v", err)
+	}
+	if got := readAllFromSource(t, r, closeFn); got != "baz\n" {
+		t.Errorf("got %q, want %q", got, "baz\n")
+	}
+}
+
+func TestOpenZipSourceConcatenatesAndDedups(t *testing.T) {
+	path := writeZipArchive(t, map[string]string{
+		"a.txt":  "foo\nbar\n",
+		"b.txt":  "bar\nbaz\n",
+		"c.json": "ignored\n",
+	})
+	r, closeFn, err := openZipSource(path, "")
+	if err != nil {
+		t.Fatalf("openZipSource: %v", err)
+	}
+	got := readAllFromSource(t, r, closeFn)
+	want := "foo\nbar\nbaz\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestOpenTarSourceSelectsMember(t *testing.T) {
+	path := writeTarArchive(t, "wordlist.tar", map[string]string{
+		"a.txt": "foo\n",
+		"b.txt": "bar\n",
+	}, false)
+	r, closeFn, err := openTarSource(path, "b.txt")
+	if err != nil {
+		t.Fatalf("openTarSource: %v", err)
+	}
+	if got := readAllFromSource(t, r, closeFn); got != "bar\n" {
+		t.Errorf("got %q, want %q", got, "bar\n")
+	}
+}
+
+func TestOpenTarGzSourceConcatenatesAndDedups(t *testing.T) {
+	path := writeTarArchive(t, "wordlist.tar.gz", map[string]string{
+		"a.txt": "foo\nbar\n",
+		"b.txt": "bar\nbaz\n",
+	}, true)
+	r, closeFn, err := openTarSource(path, "")
+	if err != nil {
+		t.Fatalf("openTarSource: %v", err)
+	}
+	got := readAllFromSource(t, r, closeFn)
+	want := "foo\nbar\nbaz\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+type closeTrackingReader struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTrackingReader) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestCloseReaderAndFileClosesBothResources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f.txt")
+	if err := os.WriteFile(path, []byte("data"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	r := &closeTrackingReader{Reader: bytes.NewBufferString("data")}
+
+	closeFn := closeReaderAndFile(r, f)
+	if err := closeFn(); err != nil {
+		t.Fatalf("closeFn: %v", err)
+	}
+	if !r.closed {
+		t.Errorf("expected the wrapped io.Closer to be closed")
+	}
+	if err := f.Close(); err == nil {
+		t.Errorf("expected f to already be closed by closeFn")
+	}
+}
+
+func TestConcatTxtMembersPreservesOrderAndDrops(t *testing.T) {
+	contents := map[string]string{
+		"1.txt": "a\nb\n",
+		"2.txt": "b\nc\n",
+	}
+	tmp, err := concatTxtMembers([]string{"1.txt", "2.txt"}, func(name string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewBufferString(contents[name])), nil
+	})
+	if err != nil {
+		t.Fatalf("concatTxtMembers: %v", err)
+	}
+	defer closeAndRemove(tmp)()
+
+	data, err := io.ReadAll(tmp)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if got, want := string(data), "a\nb\nc\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
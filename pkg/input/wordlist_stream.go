@@ -0,0 +1,349 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// DefaultStreamingThreshold is the wordlist file size, in bytes, above which
+// NewWordlistInputProvider automatically switches to StreamingWordlistInput.
+// -wordlist-stream forces streaming on for smaller files; there is no way to
+// force it off above this size.
+const DefaultStreamingThreshold = 256 * 1024 * 1024
+
+// lineIndexEntry points at a single effective wordlist entry without holding
+// its content in memory. extIdx is the index into config.Extensions to apply
+// when the entry represents an extension-expanded variant, or -1 otherwise.
+// template marks entries produced by %EXT% substitution, as opposed to the
+// plain-suffix expansion used for FUZZ keywords. line is the 1-based source
+// line the entry was read from, for Origin().
+type lineIndexEntry struct {
+	offset   int64
+	length   int32
+	extIdx   int16
+	template bool
+	line     int32
+}
+
+// StreamingWordlistInput is an InternalInputProvider that keeps only a small
+// on-disk offset index in memory instead of the full wordlist contents. It is
+// intended for multi-GB wordlists where WordlistInput's readFile would have
+// to slurp everything into w.data. Position, SetPosition and Total operate on
+// the index, and Value seeks into the source file to read the requested line
+// on demand.
+type StreamingWordlistInput struct {
+	active   bool
+	config   *ffuf.Config
+	keyword  string
+	position int
+
+	path    string
+	origin  string
+	file    *os.File
+	index   []lineIndexEntry
+	tmpPath string
+}
+
+// NewStreamingWordlistInput builds an offset index for value in a single pass
+// and returns a provider that reads entries from disk on demand. For stdin
+// (value == "-"), the input is first spooled to a temporary file so it can be
+// seeked into like any other wordlist.
+func NewStreamingWordlistInput(keyword string, value string, conf *ffuf.Config) (*StreamingWordlistInput, error) {
+	w := &StreamingWordlistInput{
+		active:  true,
+		config:  conf,
+		keyword: keyword,
+		origin:  value,
+	}
+
+	path := value
+	switch {
+	case value == "-":
+		tmpPath, err := spoolStdin()
+		if err != nil {
+			return w, err
+		}
+		w.tmpPath = tmpPath
+		path = tmpPath
+	case isArchive(archiveMemberPath(value)) || needsDecompression(value):
+		// Random-access reads in Value() need a plain, seekable file, so
+		// compressed/archived sources are decompressed once up front into
+		// a temporary file instead of being re-decompressed per seek.
+		tmpPath, err := spoolDecompressed(value)
+		if err != nil {
+			return w, err
+		}
+		w.tmpPath = tmpPath
+		path = tmpPath
+	}
+	w.path = path
+
+	if err := w.buildIndex(); err != nil {
+		return w, err
+	}
+
+	file, err := os.Open(w.path)
+	if err != nil {
+		return w, err
+	}
+	w.file = file
+
+	return w, nil
+}
+
+// spoolStdin copies stdin to a temporary file so that a streaming provider
+// can seek into it the same way it would a wordlist already on disk.
+func spoolStdin() (string, error) {
+	tmp, err := os.CreateTemp("", "ffuf-wordlist-stdin-*")
+	if err != nil {
+		return "", err
+	}
+	defer tmp.Close()
+	if _, err := io.Copy(tmp, os.Stdin); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// buildIndex scans the wordlist once, applying extension expansion and
+// shouldExcludeLine at index time so that Total() reflects the effective
+// wordlist size without requiring the content itself to stay resident.
+func (w *StreamingWordlistInput) buildIndex() error {
+	file, err := os.Open(w.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	extRe := regexp.MustCompile(`(?i)%ext%`)
+	reader := bufio.NewReader(file)
+	var offset int64
+	var lineNo int32
+
+	for {
+		raw, readErr := reader.ReadBytes('\n')
+		if len(raw) == 0 && readErr == io.EOF {
+			break
+		}
+		lineOffset := offset
+		offset += int64(len(raw))
+		lineNo++
+		pos := Position{Line: int(lineNo), Column: 1, Byte: int(lineOffset)}
+
+		trimmed := trimLineEnding(raw)
+		if w.config.DirSearchCompat && len(w.config.Extensions) > 0 && extRe.Match(trimmed) {
+			// Mirrors WordlistInput.readFile's DirSearchCompat branch: a
+			// %EXT% template line is expanded before keepLine ever runs, so
+			// comment-stripping and shouldExcludeLine don't apply to it here
+			// either - a streaming and non-streaming read of the same file
+			// and flags must produce the same entries.
+			lineLen := int32(len(trimmed))
+			for i := range w.config.Extensions {
+				w.index = append(w.index, lineIndexEntry{offset: lineOffset, length: lineLen, extIdx: int16(i), template: true, line: lineNo})
+			}
+		} else if w.config.DirSearchCompat && len(w.config.Extensions) > 0 {
+			// readFile's DirSearchCompat branch only strips comments from a
+			// line without %EXT%: no shouldExcludeLine, no extension suffixes.
+			text, keep := string(trimmed), true
+			if w.config.IgnoreWordlistComments {
+				if text, keep = stripComments(text); !keep {
+					w.logDropped(pos, "comment line")
+				}
+			}
+			if keep {
+				w.index = append(w.index, lineIndexEntry{offset: lineOffset, length: int32(len(text)), extIdx: -1, line: lineNo})
+			}
+		} else if text, keep := w.keepLine(trimmed, pos); keep {
+			// text is trimmed after stripComments, which only ever
+			// removes a trailing portion of trimmed, so its byte length
+			// is what Value() must read back at lineOffset - not
+			// len(trimmed), which still includes the stripped comment.
+			lineLen := int32(len(text))
+			w.index = append(w.index, lineIndexEntry{offset: lineOffset, length: lineLen, extIdx: -1, line: lineNo})
+			if w.keyword == "FUZZ" && len(w.config.Extensions) > 0 {
+				for i := range w.config.Extensions {
+					w.index = append(w.index, lineIndexEntry{offset: lineOffset, length: lineLen, extIdx: int16(i), line: lineNo})
+				}
+			}
+		}
+
+		if readErr != nil {
+			if readErr == io.EOF {
+				break
+			}
+			return readErr
+		}
+	}
+	return nil
+}
+
+// keepLine applies stripComments and shouldExcludeLine to a raw line,
+// returning the (possibly comment-stripped) text and whether it should be
+// kept in the index. Under -v, dropped lines are logged to stderr tagged
+// with their source position.
+func (w *StreamingWordlistInput) keepLine(line []byte, pos Position) (string, bool) {
+	text := string(line)
+	if w.config.IgnoreWordlistComments {
+		var ok bool
+		text, ok = stripComments(text)
+		if !ok {
+			w.logDropped(pos, "comment line")
+			return "", false
+		}
+	}
+	if shouldExcludeLine(text, w.config) {
+		w.logDropped(pos, "wordlist-filter")
+		return "", false
+	}
+	return text, true
+}
+
+// logDropped writes a "path:line:col: reason" diagnostic for a line excluded
+// while indexing the wordlist, matching WordlistInput's format. Only printed
+// under -v.
+func (w *StreamingWordlistInput) logDropped(pos Position, reason string) {
+	if !w.config.Verbose {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s:%s: skipping line (%s)\n", w.origin, pos, reason)
+}
+
+// trimLineEnding strips a trailing \n and, if present, \r from a line read by
+// bufio.Reader.ReadBytes, mirroring bufio.Scanner's line splitting.
+func trimLineEnding(line []byte) []byte {
+	n := len(line)
+	if n > 0 && line[n-1] == '\n' {
+		n--
+	}
+	if n > 0 && line[n-1] == '\r' {
+		n--
+	}
+	return line[:n]
+}
+
+// Position returns the current position in the input list.
+func (w *StreamingWordlistInput) Position() int {
+	return w.position
+}
+
+// SetPosition sets the current position of the inputprovider. Because the
+// offset index already has one entry per effective wordlist line, this is
+// the same O(1) operation as for WordlistInput and keeps -request-position
+// style resume working.
+func (w *StreamingWordlistInput) SetPosition(pos int) {
+	w.position = pos
+}
+
+// ResetPosition resets the position back to beginning of the wordlist.
+func (w *StreamingWordlistInput) ResetPosition() {
+	w.position = 0
+}
+
+// Keyword returns the keyword assigned to this InternalInputProvider.
+func (w *StreamingWordlistInput) Keyword() string {
+	return w.keyword
+}
+
+// Origin returns the source wordlist path and the line the entry at the
+// current cursor position was read from. See WordlistInput.Origin.
+func (w *StreamingWordlistInput) Origin() (string, Position) {
+	if w.position < len(w.index) {
+		entry := w.index[w.position]
+		return w.origin, Position{Line: int(entry.line), Column: 1, Byte: int(entry.offset)}
+	}
+	return w.origin, Position{}
+}
+
+// Next will return a boolean telling if there's words left in the list.
+func (w *StreamingWordlistInput) Next() bool {
+	return w.position < len(w.index)
+}
+
+// IncrementPosition will increment the current position in the inputprovider data slice.
+func (w *StreamingWordlistInput) IncrementPosition() {
+	w.position += 1
+}
+
+// Value seeks to the indexed offset for the current position and reads the
+// line back from disk, applying extension expansion if the entry calls for it.
+func (w *StreamingWordlistInput) Value() []byte {
+	entry := w.index[w.position]
+	buf := make([]byte, entry.length)
+	if entry.length > 0 {
+		if _, err := w.file.ReadAt(buf, entry.offset); err != nil && err != io.EOF {
+			return []byte{}
+		}
+	}
+	if entry.extIdx < 0 {
+		return buf
+	}
+	ext := []byte(w.config.Extensions[entry.extIdx])
+	if entry.template {
+		return regexp.MustCompile(`(?i)%ext%`).ReplaceAll(buf, ext)
+	}
+	return append(buf, ext...)
+}
+
+// Total returns the size of the wordlist, answered from the offset index.
+func (w *StreamingWordlistInput) Total() int {
+	return len(w.index)
+}
+
+// Active returns boolean if the inputprovider is active.
+func (w *StreamingWordlistInput) Active() bool {
+	return w.active
+}
+
+// Enable sets the inputprovider as active.
+func (w *StreamingWordlistInput) Enable() {
+	w.active = true
+}
+
+// Disable disables the inputprovider.
+func (w *StreamingWordlistInput) Disable() {
+	w.active = false
+}
+
+// Close releases the open wordlist file handle and removes the temporary
+// spool file created for stdin or compressed/archive input, if any.
+func (w *StreamingWordlistInput) Close() error {
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+	}
+	if w.tmpPath != "" {
+		os.Remove(w.tmpPath)
+	}
+	return err
+}
+
+// NewWordlistInputProvider picks between WordlistInput, StreamingWordlistInput
+// and StructuredWordlistInput for value. The structured (.ffwl) dialect is
+// opted into explicitly, via conf.WordlistFormat == "structured" or a ".ffwl"
+// extension on value, since it isn't a drop-in replacement for plain-line
+// wordlists (it carries its own per-entry metadata). Otherwise this honours
+// conf.WordlistStream when set and falls back to DefaultStreamingThreshold
+// for on-disk files; stdin has no a-priori size, so it only streams when
+// explicitly requested.
+func NewWordlistInputProvider(keyword string, value string, conf *ffuf.Config) (ffuf.InternalInputProvider, error) {
+	if conf.WordlistFormat == "structured" || strings.EqualFold(filepath.Ext(value), ".ffwl") {
+		return NewStructuredWordlistInput(keyword, value, conf)
+	}
+	if conf.WordlistStream {
+		return NewStreamingWordlistInput(keyword, value, conf)
+	}
+	if value != "-" {
+		if info, err := os.Stat(value); err == nil && info.Size() >= DefaultStreamingThreshold {
+			return NewStreamingWordlistInput(keyword, value, conf)
+		}
+	}
+	return NewWordlistInput(keyword, value, conf)
+}
@@ -0,0 +1,113 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+func writeWordlistFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "wordlist.txt")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestStreamingValueStripsInlineComments(t *testing.T) {
+	path := writeWordlistFile(t, "admin # note\nlogin\n")
+	conf := &ffuf.Config{IgnoreWordlistComments: true}
+	w, err := NewStreamingWordlistInput("FUZZ", path, conf)
+	if err != nil {
+		t.Fatalf("NewStreamingWordlistInput: %v", err)
+	}
+	defer w.Close()
+
+	if w.Total() != 2 {
+		t.Fatalf("Total() = %d, want 2", w.Total())
+	}
+	if got := string(w.Value()); got != "admin" {
+		t.Errorf("Value() = %q, want %q", got, "admin")
+	}
+	w.IncrementPosition()
+	if got := string(w.Value()); got != "login" {
+		t.Errorf("Value() = %q, want %q", got, "login")
+	}
+}
+
+func TestStreamingDropsFullLineComments(t *testing.T) {
+	path := writeWordlistFile(t, "# a full comment\nadmin\n")
+	conf := &ffuf.Config{IgnoreWordlistComments: true}
+	w, err := NewStreamingWordlistInput("FUZZ", path, conf)
+	if err != nil {
+		t.Fatalf("NewStreamingWordlistInput: %v", err)
+	}
+	defer w.Close()
+
+	if w.Total() != 1 {
+		t.Fatalf("Total() = %d, want 1", w.Total())
+	}
+	if got := string(w.Value()); got != "admin" {
+		t.Errorf("Value() = %q, want %q", got, "admin")
+	}
+}
+
+func TestStreamingDirSearchCompatMatchesWordlistInput(t *testing.T) {
+	path := writeWordlistFile(t, "#admin%EXT%\nlogin%EXT%\n")
+	conf := &ffuf.Config{
+		DirSearchCompat:        true,
+		Extensions:             []string{".php", ".html"},
+		IgnoreWordlistComments: true,
+	}
+
+	plain, err := NewWordlistInput("FUZZ", path, conf)
+	if err != nil {
+		t.Fatalf("NewWordlistInput: %v", err)
+	}
+	streamed, err := NewStreamingWordlistInput("FUZZ", path, conf)
+	if err != nil {
+		t.Fatalf("NewStreamingWordlistInput: %v", err)
+	}
+	defer streamed.Close()
+
+	if streamed.Total() != plain.Total() {
+		t.Fatalf("Total() mismatch: streamed=%d plain=%d", streamed.Total(), plain.Total())
+	}
+	for streamed.Next() {
+		if got, want := string(streamed.Value()), string(plain.Value()); got != want {
+			t.Errorf("Value() mismatch at position %d: streamed=%q plain=%q", streamed.Position(), got, want)
+		}
+		streamed.IncrementPosition()
+		plain.IncrementPosition()
+	}
+}
+
+func TestStreamingMatchesWordlistInputForSameFileAndFlags(t *testing.T) {
+	path := writeWordlistFile(t, "admin # note\nlogin\n# skip\nroot\n")
+	conf := &ffuf.Config{IgnoreWordlistComments: true}
+
+	streamed, err := NewStreamingWordlistInput("FUZZ", path, conf)
+	if err != nil {
+		t.Fatalf("NewStreamingWordlistInput: %v", err)
+	}
+	defer streamed.Close()
+
+	plain, err := NewWordlistInput("FUZZ", path, conf)
+	if err != nil {
+		t.Fatalf("NewWordlistInput: %v", err)
+	}
+
+	if streamed.Total() != plain.Total() {
+		t.Fatalf("Total() mismatch: streamed=%d plain=%d", streamed.Total(), plain.Total())
+	}
+	for streamed.Next() {
+		if got, want := string(streamed.Value()), string(plain.Value()); got != want {
+			t.Errorf("Value() mismatch at position %d: streamed=%q plain=%q", streamed.Position(), got, want)
+		}
+		streamed.IncrementPosition()
+		plain.IncrementPosition()
+	}
+}
@@ -0,0 +1,103 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+func TestWordlistInputOrigin(t *testing.T) {
+	path := writeWordlistFile(t, "admin\nlogin\n")
+	conf := &ffuf.Config{}
+	w, err := NewWordlistInput("FUZZ", path, conf)
+	if err != nil {
+		t.Fatalf("NewWordlistInput: %v", err)
+	}
+
+	gotPath, pos := w.Origin()
+	if gotPath != path {
+		t.Errorf("Origin() path = %q, want %q", gotPath, path)
+	}
+	if pos.Line != 1 {
+		t.Errorf("Origin() line = %d, want 1", pos.Line)
+	}
+
+	w.IncrementPosition()
+	if _, pos := w.Origin(); pos.Line != 2 {
+		t.Errorf("Origin() line after increment = %d, want 2", pos.Line)
+	}
+}
+
+func TestWordlistInputOriginPastEnd(t *testing.T) {
+	path := writeWordlistFile(t, "admin\n")
+	w, err := NewWordlistInput("FUZZ", path, &ffuf.Config{})
+	if err != nil {
+		t.Fatalf("NewWordlistInput: %v", err)
+	}
+	w.SetPosition(w.Total())
+	if _, pos := w.Origin(); pos != (Position{}) {
+		t.Errorf("Origin() past end = %+v, want zero value", pos)
+	}
+}
+
+func TestLegacyExcludeUppercaseExcludesNoLowercaseLines(t *testing.T) {
+	conf := &ffuf.Config{ExcludeUppercase: true}
+	cases := map[string]bool{
+		"ADMIN":  true,  // no lowercase letter: old -xc-upper semantics exclude it
+		"12345":  true,  // digits-only: no lowercase letter either
+		"admin":  false, // all lowercase, not all-upper
+		"Admin1": false, // contains a lowercase letter
+	}
+	for line, wantExcluded := range cases {
+		if got := shouldExcludeLine(line, conf); got != wantExcluded {
+			t.Errorf("shouldExcludeLine(%q) = %v, want %v", line, got, wantExcluded)
+		}
+	}
+}
+
+func TestLegacyExcludeLowercaseExcludesNoUppercaseLines(t *testing.T) {
+	conf := &ffuf.Config{ExcludeLowercase: true}
+	cases := map[string]bool{
+		"admin":  true,  // no uppercase letter: old -xc-lower semantics exclude it
+		"12345":  true,  // digits-only: no uppercase letter either
+		"ADMIN":  false, // all uppercase, not all-lower
+		"Admin1": false, // contains an uppercase letter
+	}
+	for line, wantExcluded := range cases {
+		if got := shouldExcludeLine(line, conf); got != wantExcluded {
+			t.Errorf("shouldExcludeLine(%q) = %v, want %v", line, got, wantExcluded)
+		}
+	}
+}
+
+func TestLegacyFlagsCombineWithExplicitWordlistFilter(t *testing.T) {
+	// compiledLineFilter AND-combines the desugared legacy expression with
+	// any explicit --wordlist-filter expressions, the same way repeated
+	// --wordlist-filter flags AND together - so a line must satisfy both
+	// the legacy flag's condition and the explicit filter to be excluded.
+	conf := &ffuf.Config{
+		ExcludeDotLines: true,
+		WordlistFilters: []string{`endswith(".bak")`},
+	}
+	cases := map[string]bool{
+		".admin.bak": true,  // starts with "." and ends with ".bak": both match
+		".admin":     false, // starts with "." but doesn't end with ".bak"
+		"admin.bak":  false, // ends with ".bak" but doesn't start with "."
+		"admin":      false, // matches neither
+	}
+	for line, wantExcluded := range cases {
+		if got := shouldExcludeLine(line, conf); got != wantExcluded {
+			t.Errorf("shouldExcludeLine(%q) = %v, want %v", line, got, wantExcluded)
+		}
+	}
+}
+
+func TestLegacyStartUpperUnaffectedByFix(t *testing.T) {
+	conf := &ffuf.Config{ExcludeStartUpper: true}
+	if !shouldExcludeLine("Admin", conf) {
+		t.Errorf("expected a capitalized line to be excluded")
+	}
+	if shouldExcludeLine("admin", conf) {
+		t.Errorf("expected a lowercase-starting line not to be excluded")
+	}
+}